@@ -0,0 +1,175 @@
+// Command extract scans the module for translatable calls made through the
+// messages package (Printer.Sprintf / Printer.Printf) and writes their
+// source strings to a gotext-style messages.gotext.json catalog, ready for
+// translators to fill in.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+type catalogEntry struct {
+	ID          string `json:"id"`
+	Translation string `json:"translation"`
+}
+
+type catalogFile struct {
+	Language string         `json:"language"`
+	Messages []catalogEntry `json:"messages"`
+}
+
+func main() {
+	dir := flag.String("dir", ".", "root directory to scan for Go source")
+	out := flag.String("out", "messages.gotext.json", "path to write the extracted catalog")
+	lang := flag.String("lang", "en", "language tag to stamp the catalog with")
+	flag.Parse()
+
+	ids, err := extract(*dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "extract:", err)
+		os.Exit(1)
+	}
+
+	cf := catalogFile{Language: *lang}
+	for _, id := range ids {
+		cf.Messages = append(cf.Messages, catalogEntry{ID: id})
+	}
+
+	data, err := json.MarshalIndent(cf, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "extract: marshal catalog:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*out, data, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "extract: write catalog:", err)
+		os.Exit(1)
+	}
+}
+
+// extract walks dir for .go files and collects the source-string argument of
+// every messages.Printer Sprintf/Printf call it finds, in file order.
+func extract(dir string) ([]string, error) {
+	var ids []string
+	seen := make(map[string]bool)
+
+	fset := token.NewFileSet()
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".go" {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+			printerVars := printerParams(fn.Type)
+
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				if sel.Sel.Name != "Sprintf" && sel.Sel.Name != "Printf" {
+					return true
+				}
+				if !isPrinterCall(sel.X, printerVars) {
+					return true
+				}
+				if len(call.Args) == 0 {
+					return true
+				}
+				lit, ok := call.Args[0].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return true
+				}
+				id, err := strconv.Unquote(lit.Value)
+				if err != nil || seen[id] {
+					return true
+				}
+				seen[id] = true
+				ids = append(ids, id)
+				return true
+			})
+		}
+		return nil
+	})
+	return ids, err
+}
+
+// printerParams returns the names of fn's parameters declared as
+// *message.Printer or *messages.Printer, the only receiver types the
+// Sprintf/Printf calls we extract are made through.
+func printerParams(fn *ast.FuncType) map[string]bool {
+	vars := make(map[string]bool)
+	if fn.Params == nil {
+		return vars
+	}
+	for _, field := range fn.Params.List {
+		if !isPrinterType(field.Type) {
+			continue
+		}
+		for _, name := range field.Names {
+			vars[name.Name] = true
+		}
+	}
+	return vars
+}
+
+// isPrinterType reports whether typ is *message.Printer or *messages.Printer.
+func isPrinterType(typ ast.Expr) bool {
+	star, ok := typ.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Printer" {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && (pkg.Name == "message" || pkg.Name == "messages")
+}
+
+// isPrinterCall reports whether recv is a value known to be a message
+// printer: either one of the current function's printer-typed parameters,
+// or a call directly constructing one, e.g. messages.Printer(tag) or
+// message.NewPrinter(tag).
+func isPrinterCall(recv ast.Expr, printerVars map[string]bool) bool {
+	switch e := recv.(type) {
+	case *ast.Ident:
+		return printerVars[e.Name]
+	case *ast.CallExpr:
+		sel, ok := e.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return false
+		}
+		return (pkg.Name == "messages" && sel.Sel.Name == "Printer") ||
+			(pkg.Name == "message" && sel.Sel.Name == "NewPrinter")
+	default:
+		return false
+	}
+}
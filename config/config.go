@@ -0,0 +1,109 @@
+// Package config loads flat's server configuration from an XDG-compliant
+// YAML file, with environment variable overrides for common fields.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/adrg/xdg"
+	"gopkg.in/yaml.v3"
+)
+
+// VirtualHost is one named virtual host entry in the config file.
+type VirtualHost struct {
+	Name string `yaml:"name"`
+	Host string `yaml:"host"`
+}
+
+// Config is flat's on-disk configuration.
+type Config struct {
+	Host        string        `yaml:"host"`
+	Port        int           `yaml:"port"`
+	TLSCertFile string        `yaml:"tls_cert_file"`
+	TLSKeyFile  string        `yaml:"tls_key_file"`
+	LogLevel    string        `yaml:"log_level"`
+	VirtualHost []VirtualHost `yaml:"virtual_hosts"`
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		Host:     "127.0.0.1",
+		Port:     8080,
+		LogLevel: "info",
+	}
+}
+
+// Path returns the XDG-compliant location of flat's config file: it honors
+// $XDG_CONFIG_HOME and falls back to ~/.config/flat/config.yaml.
+func Path() (string, error) {
+	return xdg.ConfigFile("flat/config.yaml")
+}
+
+// LoadConfig loads the YAML config file at its XDG path, writing a default
+// config on first run, and applies FLAT_HOST/FLAT_PORT environment
+// overrides on top of whatever was read.
+func LoadConfig() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, fmt.Errorf("config: resolve config path: %w", err)
+	}
+	return LoadConfigFrom(path)
+}
+
+// LoadConfigFrom loads the YAML config file at an explicit path, e.g. one
+// supplied via a --config flag, writing a default config on first run and
+// applying FLAT_HOST/FLAT_PORT environment overrides.
+func LoadConfigFrom(path string) (*Config, error) {
+	cfg, err := loadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	applyEnvOverrides(cfg)
+	return cfg, nil
+}
+
+func loadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		cfg := defaultConfig()
+		if writeErr := writeDefault(path, cfg); writeErr != nil {
+			return nil, fmt.Errorf("config: write default config: %w", writeErr)
+		}
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config: read %s: %w", path, err)
+	}
+
+	cfg := defaultConfig()
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("config: parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func writeDefault(path string, cfg *Config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("config: marshal default config: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+func applyEnvOverrides(cfg *Config) {
+	if host := os.Getenv("FLAT_HOST"); host != "" {
+		cfg.Host = host
+	}
+	if port := os.Getenv("FLAT_PORT"); port != "" {
+		if p, err := strconv.Atoi(port); err == nil {
+			cfg.Port = p
+		}
+	}
+}
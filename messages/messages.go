@@ -0,0 +1,76 @@
+// Package messages provides translatable strings for flat's server output,
+// following the extract/catalog approach used by golang.org/x/text/message.
+package messages
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"github.com/zkoranges/flat/nameformat"
+)
+
+// catalogEntry mirrors a single message in a gotext-style catalog file.
+type catalogEntry struct {
+	ID          string `json:"id"`
+	Translation string `json:"translation"`
+}
+
+// catalogFile mirrors the messages.gotext.json format written by cmd/extract.
+type catalogFile struct {
+	Language string         `json:"language"`
+	Messages []catalogEntry `json:"messages"`
+}
+
+// LoadCatalog reads a gotext-style JSON catalog from path and registers its
+// translations with the package-level message catalog, returning the
+// language tag the catalog was written for.
+func LoadCatalog(path string) (language.Tag, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return language.Und, fmt.Errorf("messages: read catalog %s: %w", path, err)
+	}
+
+	var cf catalogFile
+	if err := json.Unmarshal(data, &cf); err != nil {
+		return language.Und, fmt.Errorf("messages: parse catalog %s: %w", path, err)
+	}
+
+	tag, err := language.Parse(cf.Language)
+	if err != nil {
+		return language.Und, fmt.Errorf("messages: invalid language %q in %s: %w", cf.Language, path, err)
+	}
+
+	for _, m := range cf.Messages {
+		if m.Translation == "" {
+			continue
+		}
+		if err := message.SetString(tag, m.ID, m.Translation); err != nil {
+			return language.Und, fmt.Errorf("messages: register %q: %w", m.ID, err)
+		}
+	}
+	return tag, nil
+}
+
+// Printer returns a message.Printer for tag. Keys with no registered
+// translation fall back to the English source string passed to Sprintf.
+func Printer(tag language.Tag) *message.Printer {
+	return message.NewPrinter(tag)
+}
+
+// StartingServer renders the "server is starting" message for addr in the
+// printer's locale.
+func StartingServer(p *message.Printer, addr string) string {
+	return p.Sprintf("Starting server on %s", addr)
+}
+
+// Honorific returns the localized honorific for gender, translating
+// nameformat.DefaultHonorific's English default ("Mr."/"Ms."/"Mx.") through
+// p. This is the gender/plural selection seam for the "honorific"
+// nameformat style; see Server.FormatName.
+func Honorific(p *message.Printer, gender nameformat.Gender) string {
+	return p.Sprintf(nameformat.DefaultHonorific(gender))
+}
@@ -0,0 +1,127 @@
+// Package nameformat arranges a name's parts into a display string using a
+// pluggable, registry-selected style.
+package nameformat
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Gender selects the honorific the "honorific" style falls back to when
+// NameParts.Honorific is not supplied.
+type Gender int
+
+const (
+	GenderUnspecified Gender = iota
+	GenderMale
+	GenderFemale
+)
+
+// NameParts holds the components a Formatter draws on. Not every style uses
+// every field: Parts and MaxLength exist solely for the "schema-style"
+// joiner, which formats identifier segments rather than a person's name.
+type NameParts struct {
+	First     string
+	Last      string
+	Honorific string
+	Gender    Gender
+
+	Parts     []string
+	MaxLength int
+}
+
+// Formatter arranges NameParts into a single display string.
+type Formatter interface {
+	Format(parts NameParts) string
+}
+
+// FormatterFunc adapts a plain function to the Formatter interface.
+type FormatterFunc func(NameParts) string
+
+func (f FormatterFunc) Format(parts NameParts) string {
+	return f(parts)
+}
+
+var registry = map[string]Formatter{}
+
+func init() {
+	Register("western", FormatterFunc(formatWestern))
+	Register("eastern", FormatterFunc(formatEastern))
+	Register("honorific", FormatterFunc(formatHonorific))
+	Register("initials", FormatterFunc(formatInitials))
+	Register("schema-style", FormatterFunc(formatSchema))
+}
+
+// Register adds a Formatter under name, replacing any existing registration.
+func Register(name string, f Formatter) {
+	registry[name] = f
+}
+
+// Format arranges parts using the Formatter registered under style.
+func Format(style string, parts NameParts) (string, error) {
+	f, ok := registry[style]
+	if !ok {
+		return "", fmt.Errorf("nameformat: unknown style %q", style)
+	}
+	return f.Format(parts), nil
+}
+
+// formatWestern renders "First Last".
+func formatWestern(parts NameParts) string {
+	return strings.TrimSpace(parts.First + " " + parts.Last)
+}
+
+// formatEastern renders "Last First".
+func formatEastern(parts NameParts) string {
+	return strings.TrimSpace(parts.Last + " " + parts.First)
+}
+
+// formatHonorific renders "Mr./Ms. Last", defaulting to the honorific for
+// parts.Gender when no Honorific was supplied.
+func formatHonorific(parts NameParts) string {
+	honorific := parts.Honorific
+	if honorific == "" {
+		honorific = DefaultHonorific(parts.Gender)
+	}
+	return strings.TrimSpace(honorific + " " + parts.Last)
+}
+
+// DefaultHonorific returns the English honorific the "honorific" style falls
+// back to for gender: "Mr." for GenderMale, "Ms." for GenderFemale, and the
+// gender-neutral "Mx." for GenderUnspecified. Callers that localize output,
+// such as the messages package, translate the returned string rather than
+// hardcoding it.
+func DefaultHonorific(gender Gender) string {
+	switch gender {
+	case GenderMale:
+		return "Mr."
+	case GenderFemale:
+		return "Ms."
+	default:
+		return "Mx."
+	}
+}
+
+// formatInitials renders "F. Last". The initial is taken from the first
+// rune of First, not the first byte, so multi-byte given names (e.g.
+// "Émile") still produce a valid initial.
+func formatInitials(parts NameParts) string {
+	if parts.First == "" {
+		return strings.TrimSpace(parts.Last)
+	}
+	r, _ := utf8.DecodeRuneInString(parts.First)
+	return fmt.Sprintf("%c. %s", unicode.ToUpper(r), parts.Last)
+}
+
+// formatSchema joins Parts with underscores and truncates to MaxLength bytes,
+// the style used for identifiers such as "prefix_table_col" built from
+// Parts {"prefix", "table", "col"} rather than person names.
+func formatSchema(parts NameParts) string {
+	joined := strings.Join(parts.Parts, "_")
+	if parts.MaxLength > 0 && len(joined) > parts.MaxLength {
+		joined = joined[:parts.MaxLength]
+	}
+	return joined
+}
@@ -1,25 +1,289 @@
 package main
 
 import (
-    "fmt"
-    "strings"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/text/language"
+
+	"github.com/zkoranges/flat/config"
+	"github.com/zkoranges/flat/messages"
+	"github.com/zkoranges/flat/nameformat"
 )
 
+// Middleware wraps an http.Handler to add cross-cutting behavior such as
+// logging, panic recovery, or request tagging.
+type Middleware func(http.Handler) http.Handler
+
+// Server is a small net/http wrapper that adds graceful shutdown and a
+// middleware chain on top of the standard library server.
 type Server struct {
-    Host string
-    Port int
+	Host    string
+	Port    int
+	Handler http.Handler
+
+	middleware  []Middleware
+	httpMu      sync.Mutex
+	http        *http.Server
+	locale      language.Tag
+	logLevel    logLevel
+	tlsCertFile string
+	tlsKeyFile  string
 }
 
 func NewServer(host string, port int) *Server {
-    return &Server{Host: host, Port: port}
+	return &Server{
+		Host:    host,
+		Port:    port,
+		Handler: http.NewServeMux(),
+		locale:  language.English,
+	}
+}
+
+// NewServerFromConfig builds a Server from a loaded config.Config, wiring up
+// the TLS certificate paths that Run uses to choose between Start and
+// StartTLS, and the log level that loggingMiddleware checks before logging.
+func NewServerFromConfig(cfg *config.Config) *Server {
+	s := NewServer(cfg.Host, cfg.Port)
+	s.tlsCertFile = cfg.TLSCertFile
+	s.tlsKeyFile = cfg.TLSKeyFile
+	s.logLevel = parseLogLevel(cfg.LogLevel)
+	return s
+}
+
+// logLevel identifies how verbose the server's own logging should be,
+// driven by config.Config.LogLevel.
+type logLevel int
+
+const (
+	logLevelDebug logLevel = iota
+	logLevelInfo
+	logLevelWarn
+	logLevelError
+)
+
+// parseLogLevel maps a config.Config.LogLevel string ("debug", "info",
+// "warn"/"warning", or "error") to a logLevel, defaulting to logLevelInfo
+// for an empty or unrecognized value.
+func parseLogLevel(s string) logLevel {
+	switch strings.ToLower(s) {
+	case "debug":
+		return logLevelDebug
+	case "warn", "warning":
+		return logLevelWarn
+	case "error":
+		return logLevelError
+	default:
+		return logLevelInfo
+	}
+}
+
+// WithLocale sets the language used for the server's own translatable
+// output (startup logs, etc.) and returns s for chaining.
+func (s *Server) WithLocale(tag language.Tag) *Server {
+	s.locale = tag
+	return s
+}
+
+// Use appends middleware to the chain. Middleware runs in the order it was
+// added, with the first registered wrapping all the others.
+func (s *Server) Use(mw ...Middleware) {
+	s.middleware = append(s.middleware, mw...)
+}
+
+// Handle registers a handler for the given pattern on the server's mux.
+// It panics if Handler is not an *http.ServeMux, mirroring the stdlib's own
+// behavior for http.Handle.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	mux, ok := s.Handler.(*http.ServeMux)
+	if !ok {
+		panic("flat: Handle requires Handler to be an *http.ServeMux")
+	}
+	mux.Handle(pattern, handler)
+}
+
+// HandleFunc registers a handler function for the given pattern.
+func (s *Server) HandleFunc(pattern string, handler http.HandlerFunc) {
+	s.Handle(pattern, handler)
+}
+
+func (s *Server) chain() http.Handler {
+	handler := s.Handler
+	if handler == nil {
+		handler = http.NewServeMux()
+	}
+	for i := len(s.middleware) - 1; i >= 0; i-- {
+		handler = s.middleware[i](handler)
+	}
+	return handler
+}
+
+func (s *Server) addr() string {
+	return fmt.Sprintf("%s:%d", s.Host, s.Port)
+}
+
+// setHTTPServer installs the *http.Server that Start/StartTLS built, so a
+// concurrent Shutdown call never observes s.http mid-assignment.
+func (s *Server) setHTTPServer(h *http.Server) {
+	s.httpMu.Lock()
+	defer s.httpMu.Unlock()
+	s.http = h
+}
+
+func (s *Server) httpServer() *http.Server {
+	s.httpMu.Lock()
+	defer s.httpMu.Unlock()
+	return s.http
 }
 
+// Start runs the server on Host:Port and blocks until it stops. It returns
+// nil if the server was shut down cleanly via Shutdown.
 func (s *Server) Start() error {
-    addr := fmt.Sprintf("%s:%d", s.Host, s.Port)
-    fmt.Println("Starting server on", addr)
-    return nil
+	addr := s.addr()
+	fmt.Println(messages.StartingServer(messages.Printer(s.locale), addr))
+
+	h := &http.Server{
+		Addr:    addr,
+		Handler: s.chain(),
+	}
+	s.setHTTPServer(h)
+
+	if err := h.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// StartTLS runs the server on Host:Port using the given certificate and key
+// files. Like Start, it blocks until the server stops.
+func (s *Server) StartTLS(certFile, keyFile string) error {
+	addr := s.addr()
+	fmt.Println(messages.StartingServer(messages.Printer(s.locale), addr))
+
+	h := &http.Server{
+		Addr:    addr,
+		Handler: s.chain(),
+	}
+	s.setHTTPServer(h)
+
+	if err := h.ListenAndServeTLS(certFile, keyFile); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Run starts the server, using StartTLS when the config supplied a
+// certificate and key, or plain Start otherwise.
+func (s *Server) Run() error {
+	if s.tlsCertFile != "" && s.tlsKeyFile != "" {
+		return s.StartTLS(s.tlsCertFile, s.tlsKeyFile)
+	}
+	return s.Start()
+}
+
+// Shutdown gracefully shuts down the server without interrupting active
+// connections, respecting ctx's deadline.
+func (s *Server) Shutdown(ctx context.Context) error {
+	h := s.httpServer()
+	if h == nil {
+		return nil
+	}
+	return h.Shutdown(ctx)
+}
+
+// loggingMiddleware logs the method, path, and duration of each request,
+// provided s's configured log level is logLevelInfo or more verbose.
+func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		if s.logLevel <= logLevelInfo {
+			log.Printf("%s %s %s", r.Method, r.URL.Path, time.Since(start))
+		}
+	})
+}
+
+// recoveryMiddleware converts panics in downstream handlers into 500
+// responses instead of crashing the server.
+func recoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Printf("panic handling %s %s: %v", r.Method, r.URL.Path, rec)
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+type requestIDKey struct{}
+
+// requestIDMiddleware tags each request with a unique ID, stored in the
+// request context and echoed back as the X-Request-Id header.
+func requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := newRequestID()
+		w.Header().Set("X-Request-Id", id)
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
 }
 
+func newRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// formatName is a thin wrapper over the "western" nameformat.Formatter,
+// kept for callers that predate the formatter registry.
 func formatName(first, last string) string {
-    return strings.TrimSpace(first + " " + last)
+	name, _ := nameformat.Format("western", nameformat.NameParts{First: first, Last: last})
+	return name
+}
+
+// FormatName renders parts using the "honorific" nameformat style,
+// localizing the Mr./Ms./Mx. honorific to s's configured locale (see
+// WithLocale) when parts.Honorific is not set explicitly.
+func (s *Server) FormatName(parts nameformat.NameParts) string {
+	if parts.Honorific == "" {
+		parts.Honorific = messages.Honorific(messages.Printer(s.locale), parts.Gender)
+	}
+	name, _ := nameformat.Format("honorific", parts)
+	return name
+}
+
+func main() {
+	configPath := flag.String("config", "", "path to config.yaml (defaults to the XDG config location)")
+	flag.Parse()
+
+	var cfg *config.Config
+	var err error
+	if *configPath != "" {
+		cfg, err = config.LoadConfigFrom(*configPath)
+	} else {
+		cfg, err = config.LoadConfig()
+	}
+	if err != nil {
+		log.Fatalf("flat: load config: %v", err)
+	}
+
+	s := NewServerFromConfig(cfg)
+	s.Use(recoveryMiddleware, s.loggingMiddleware, requestIDMiddleware)
+
+	if err := s.Run(); err != nil {
+		log.Fatalf("flat: server error: %v", err)
+	}
 }